@@ -91,14 +91,14 @@ the terms of any one of the MPL, the GPL or the LGPL.
 // Resource structure in pseudo Go:
 //
 //	Resource {
-//		Collection map[string]interface{} {
+//		Collection {
 //			Version: "1.0",
 //			Href: "/api/",
 //			Links: []Link,
 //			Items: []Item,
 //			Queries: []Query,
-//			Template: Template,
-//			Error: Error
+//			Template: *Template,
+//			Error: *Error
 //		}
 //	}
 //
@@ -178,9 +178,37 @@ import (
 //    responseWriter.Header().Set("Content-Type", cljs.ContentType)
 var ContentType = "application/vnd.collection+json"
 
+// Collection is the body of a Resource. Field order matches the wire
+// format documented above: version and href first, followed by the
+// optional sections, which are omitted entirely when empty.
+//
+// Collection used to be a map[string]interface{}, requiring every reader
+// (Validate, Marshal, ...) to type-assert its way back to []Link, []Item,
+// []Query, Template and Error; storing the wrong concrete type in the map
+// was a runtime panic waiting to happen, and AddQuery's reset-then-append
+// code, written against a Queries field the map did not have, did not
+// compile at all. There is deliberately
+// no map-shaped shim: this package's public surface has always been
+// New/AddLink/AddItem/AddQuery/SetTemplate/SetError/Marshal/Validate, none
+// of which ever required a caller to index Collection directly, and the
+// JSON produced carries the same members as before; only their order
+// changes, from the alphabetical key order of a marshalled map to the
+// field order above. Only
+// Go code that indexed Collection as a map needs to move to these fields
+// or, preferably, to the Add*/Set* helpers.
+type Collection struct {
+	Version  string    `json:"version"`
+	Href     string    `json:"href"`
+	Links    []Link    `json:"links,omitempty"`
+	Items    []Item    `json:"items,omitempty"`
+	Queries  []Query   `json:"queries,omitempty"`
+	Template *Template `json:"template,omitempty"`
+	Error    *Error    `json:"error,omitempty"`
+}
+
 // Resource is a top-level document returned by an API
 type Resource struct {
-	Collection map[string]interface{} `json:"collection"`
+	Collection Collection `json:"collection"`
 	mutex      sync.Mutex
 }
 
@@ -189,15 +217,14 @@ type Resource struct {
 // should be set to the URL to the root of the API.
 func New(root string) *Resource {
 	var r Resource
-	r.Collection = make(map[string]interface{})
-	r.Collection["version"] = "1.0"
-	r.Collection["href"] = root
+	r.Collection.Version = "1.0"
+	r.Collection.Href = root
 	return &r
 }
 
 // Marshal validates the syntax of a Resource and returns its json encoded
 // version in a byte array.
-func (r Resource) Marshal() (rj []byte, err error) {
+func (r *Resource) Marshal() (rj []byte, err error) {
 	err = r.Validate()
 	if err != nil {
 		err = fmt.Errorf("Resource marshalling failed with error '%v'", err)
@@ -213,65 +240,48 @@ func (r Resource) Marshal() (rj []byte, err error) {
 }
 
 // Validate makes sure that the Resource conforms to the standard syntax
-func (r Resource) Validate() (err error) {
-	if _, ok := r.Collection["version"]; !ok {
+func (r *Resource) Validate() (err error) {
+	if r.Collection.Version == "" {
 		return fmt.Errorf("version is missing. Must be '1.0'")
 	}
-	if r.Collection["version"] != "1.0" {
+	if r.Collection.Version != "1.0" {
 		return fmt.Errorf("wrong version. Must be '1.0'")
 	}
 
-	if _, ok := r.Collection["href"]; !ok {
-		return fmt.Errorf("document base 'href' is missing")
-	}
-	if r.Collection["href"] == "" {
+	if r.Collection.Href == "" {
 		return fmt.Errorf("'href' is empty. Must contains resource location")
 	}
 
-	if _, ok := r.Collection["links"]; ok {
-		var links []Link
-		links = r.Collection["links"].([]Link)
-		for i, link := range links {
-			err = link.Validate()
-			if err != nil {
-				return fmt.Errorf("failed to validate link %d: %v", i, err)
-			}
+	for i, link := range r.Collection.Links {
+		err = link.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate link %d: %v", i, err)
 		}
 	}
 
-	if _, ok := r.Collection["items"]; ok {
-		var items []Item
-		items = r.Collection["items"].([]Item)
-		for i, item := range items {
-			err = item.Validate()
-			if err != nil {
-				return fmt.Errorf("failed to validate item %d: %v", i, err)
-			}
+	for i, item := range r.Collection.Items {
+		err = item.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate item %d: %v", i, err)
 		}
 	}
 
-	if _, ok := r.Collection["queries"]; ok {
-		var queries []Query
-		queries = r.Collection["queries"].([]Query)
-		for i, query := range queries {
-			err = query.Validate()
-			if err != nil {
-				return fmt.Errorf("failed to validate query %d: %v", i, err)
-			}
+	for i, query := range r.Collection.Queries {
+		err = query.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate query %d: %v", i, err)
 		}
 	}
 
-	if _, ok := r.Collection["template"]; ok {
-		template := r.Collection["template"].(Template)
-		err = template.Validate()
+	if r.Collection.Template != nil {
+		err = r.Collection.Template.Validate()
 		if err != nil {
 			return fmt.Errorf("failed to validate template: %v", err)
 		}
 	}
 
-	if _, ok := r.Collection["error"]; ok {
-		res_error := r.Collection["error"].(Error)
-		err = res_error.Validate()
+	if r.Collection.Error != nil {
+		err = r.Collection.Error.Validate()
 		if err != nil {
 			return fmt.Errorf("failed to validate resource error: %v", err)
 		}
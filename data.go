@@ -0,0 +1,162 @@
+/* Go module for Collection+JSON
+
+Version: MPL 1.1/GPL 2.0/LGPL 2.1
+
+The contents of this file are subject to the Mozilla Public License Version
+1.1 (the "License"); you may not use this file except in compliance with
+the License. You may obtain a copy of the License at
+http://www.mozilla.org/MPL/
+
+Software distributed under the License is distributed on an "AS IS" basis,
+WITHOUT WARRANTY OF ANY KIND, either express or implied. See the License
+for the specific language governing rights and limitations under the
+License.
+
+The Initial Developer of the Original Code is
+Mozilla Corporation
+Portions created by the Initial Developer are Copyright (C) 2014
+the Initial Developer. All Rights Reserved.
+
+Contributor(s):
+Julien Vehent jvehent@mozilla.com [:ulfr]
+
+Alternatively, the contents of this file may be used under the terms of
+either the GNU General Public License Version 2 or later (the "GPL"), or
+the GNU Lesser General Public License Version 2.1 or later (the "LGPL"),
+in which case the provisions of the GPL or the LGPL are applicable instead
+of those above. If you wish to allow use of your version of this file only
+under the terms of either the GPL or the LGPL, and not to allow others to
+use your version of this file under the terms of the MPL, indicate your
+decision by deleting the provisions above and replace them with the notice
+and other provisions required by the GPL or the LGPL. If you do not delete
+the provisions above, a recipient may use your version of this file under
+the terms of any one of the MPL, the GPL or the LGPL.
+*/
+
+package cljs
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Data represents a single name/value pair carried by an Item, a Query or
+// a Template, as described in the Collection+JSON standard. Type, Required,
+// ReadOnly, Regex, Min and Max are not part of the baseline spec: they are
+// the widely implemented Cj "value extensions" used to describe and
+// enforce constraints on a template's fields.
+//
+// Required and ReadOnly are constraints on a *stored* template's fields,
+// not properties Validate can check in isolation: a schema's required
+// field is legitimately unset until a client fills it in, and whether a
+// posted value differs from a read-only one can only be judged by
+// comparing it against the schema. Both are enforced by Template.Apply,
+// which has both the schema and the posted value on hand; Validate only
+// checks Type and Regex, which are properties of a single Data value.
+type Data struct {
+	Name     string      `json:"name"`               //required
+	Value    interface{} `json:"value,omitempty"`    //optional
+	Prompt   string      `json:"prompt,omitempty"`   //optional
+	Type     string      `json:"type,omitempty"`     //optional, one of string/number/boolean/date-time/array/object
+	Required bool        `json:"required,omitempty"` //optional, enforced by Template.Apply
+	ReadOnly bool        `json:"readOnly,omitempty"` //optional, enforced by Template.Apply
+	Regex    string      `json:"regex,omitempty"`    //optional
+	Min      *float64    `json:"min,omitempty"`      //optional
+	Max      *float64    `json:"max,omitempty"`      //optional
+
+	re *regexp.Regexp // cached compilation of Regex, set by compileRegex
+}
+
+// compileRegex compiles Regex once and caches the result on data, so
+// Validate never recompiles a pattern it has already checked. Template.Validate
+// calls this eagerly for every field, so a malformed pattern is rejected as
+// soon as a template is set or parsed, rather than only when a client later
+// posts a matching string value.
+func (data *Data) compileRegex() (err error) {
+	if data.Regex == "" || data.re != nil {
+		return nil
+	}
+	data.re, err = regexp.Compile(data.Regex)
+	if err != nil {
+		return fmt.Errorf("'%s' has an invalid regex %q: %v", data.Name, data.Regex, err)
+	}
+	return nil
+}
+
+func (data Data) Validate() (err error) {
+	if data.Name == "" {
+		return fmt.Errorf("'name' attr is empty")
+	}
+	if data.Regex != "" {
+		if s, ok := data.Value.(string); ok {
+			re := data.re
+			if re == nil {
+				re, err = regexp.Compile(data.Regex)
+				if err != nil {
+					return fmt.Errorf("'%s' has an invalid regex %q: %v", data.Name, data.Regex, err)
+				}
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("'%s' value %q does not match regex %q", data.Name, s, data.Regex)
+			}
+		}
+	}
+	if data.Type != "" {
+		err = data.validateType()
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+// validateType checks Value against Type, and against Min/Max when Type is
+// "number". A nil Value is not typed: whether it is acceptable is up to
+// Required, checked separately by Template.Apply.
+func (data Data) validateType() (err error) {
+	if data.Value == nil {
+		return nil
+	}
+	switch data.Type {
+	case "string":
+		if _, ok := data.Value.(string); !ok {
+			return fmt.Errorf("'%s' must be a string", data.Name)
+		}
+	case "number":
+		v, ok := data.Value.(float64)
+		if !ok {
+			return fmt.Errorf("'%s' must be a number", data.Name)
+		}
+		if data.Min != nil && v < *data.Min {
+			return fmt.Errorf("'%s' value %v is below the minimum of %v", data.Name, v, *data.Min)
+		}
+		if data.Max != nil && v > *data.Max {
+			return fmt.Errorf("'%s' value %v is above the maximum of %v", data.Name, v, *data.Max)
+		}
+	case "boolean":
+		if _, ok := data.Value.(bool); !ok {
+			return fmt.Errorf("'%s' must be a boolean", data.Name)
+		}
+	case "date-time":
+		s, ok := data.Value.(string)
+		if !ok {
+			return fmt.Errorf("'%s' must be a date-time string", data.Name)
+		}
+		_, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("'%s' is not a valid RFC3339 date-time: %v", data.Name, err)
+		}
+	case "array":
+		if _, ok := data.Value.([]interface{}); !ok {
+			return fmt.Errorf("'%s' must be an array", data.Name)
+		}
+	case "object":
+		if _, ok := data.Value.(map[string]interface{}); !ok {
+			return fmt.Errorf("'%s' must be an object", data.Name)
+		}
+	default:
+		return fmt.Errorf("'%s' has unknown type %q", data.Name, data.Type)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/* Go module for Collection+JSON
+
+Version: MPL 1.1/GPL 2.0/LGPL 2.1
+
+The contents of this file are subject to the Mozilla Public License Version
+1.1 (the "License"); you may not use this file except in compliance with
+the License. You may obtain a copy of the License at
+http://www.mozilla.org/MPL/
+
+Software distributed under the License is distributed on an "AS IS" basis,
+WITHOUT WARRANTY OF ANY KIND, either express or implied. See the License
+for the specific language governing rights and limitations under the
+License.
+
+The Initial Developer of the Original Code is
+Mozilla Corporation
+Portions created by the Initial Developer are Copyright (C) 2014
+the Initial Developer. All Rights Reserved.
+
+Contributor(s):
+Julien Vehent jvehent@mozilla.com [:ulfr]
+
+Alternatively, the contents of this file may be used under the terms of
+either the GNU General Public License Version 2 or later (the "GPL"), or
+the GNU Lesser General Public License Version 2.1 or later (the "LGPL"),
+in which case the provisions of the GPL or the LGPL are applicable instead
+of those above. If you wish to allow use of your version of this file only
+under the terms of either the GPL or the LGPL, and not to allow others to
+use your version of this file under the terms of the MPL, indicate your
+decision by deleting the provisions above and replace them with the notice
+and other provisions required by the GPL or the LGPL. If you do not delete
+the provisions above, a recipient may use your version of this file under
+the terms of any one of the MPL, the GPL or the LGPL.
+*/
+
+package cljs
+
+import (
+	"fmt"
+)
+
+// Error carries the details of a failure, to be returned to the client
+// in place of the requested resource.
+type Error struct {
+	Title   string `json:"title,omitempty"`   //optional
+	Code    string `json:"code,omitempty"`    //optional
+	Message string `json:"message,omitempty"` //optional
+}
+
+func (r *Resource) SetError(errval Error) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// syntax checking
+	err = errval.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate error: %v", err)
+	}
+	r.Collection.Error = &errval
+	return
+}
+
+func (errval Error) Validate() (err error) {
+	if errval.Message == "" {
+		return fmt.Errorf("'message' attr is empty")
+	}
+	return
+}
@@ -0,0 +1,156 @@
+/* Go module for Collection+JSON
+
+Version: MPL 1.1/GPL 2.0/LGPL 2.1
+
+The contents of this file are subject to the Mozilla Public License Version
+1.1 (the "License"); you may not use this file except in compliance with
+the License. You may obtain a copy of the License at
+http://www.mozilla.org/MPL/
+
+Software distributed under the License is distributed on an "AS IS" basis,
+WITHOUT WARRANTY OF ANY KIND, either express or implied. See the License
+for the specific language governing rights and limitations under the
+License.
+
+The Initial Developer of the Original Code is
+Mozilla Corporation
+Portions created by the Initial Developer are Copyright (C) 2014
+the Initial Developer. All Rights Reserved.
+
+Contributor(s):
+Julien Vehent jvehent@mozilla.com [:ulfr]
+
+Alternatively, the contents of this file may be used under the terms of
+either the GNU General Public License Version 2 or later (the "GPL"), or
+the GNU Lesser General Public License Version 2.1 or later (the "LGPL"),
+in which case the provisions of the GPL or the LGPL are applicable instead
+of those above. If you wish to allow use of your version of this file only
+under the terms of either the GPL or the LGPL, and not to allow others to
+use your version of this file under the terms of the MPL, indicate your
+decision by deleting the provisions above and replace them with the notice
+and other provisions required by the GPL or the LGPL. If you do not delete
+the provisions above, a recipient may use your version of this file under
+the terms of any one of the MPL, the GPL or the LGPL.
+*/
+
+package cljs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// CodeStatus maps a Resource error's Code to the HTTP status Handler
+// should reply with. Callers may add their own codes before serving.
+var CodeStatus = map[string]int{
+	"400": http.StatusBadRequest,
+	"401": http.StatusUnauthorized,
+	"403": http.StatusForbidden,
+	"404": http.StatusNotFound,
+	"409": http.StatusConflict,
+	"500": http.StatusInternalServerError,
+}
+
+// Handler adapts a Resource to net/http: it negotiates the response
+// Content-Type, writes the Resource's body, maps a set Error to its HTTP
+// status via CodeStatus, and, on POST/PUT, runs the request body through
+// Unmarshal/ApplyTemplate before handing the resulting Item to OnWrite.
+type Handler struct {
+	// Resource is served on GET and updated, once OnWrite has approved the
+	// posted Item, by AddItem on POST or ReplaceItem on PUT: POST always
+	// adds a new Item, while PUT replaces any existing Item with the same
+	// Href so a repeated or retried PUT converges on one Item.
+	Resource *Resource
+	// OnWrite, when set, is called with the Item built from the posted
+	// template and returns the Item to persist and add to Resource. If
+	// OnWrite is nil, POST and PUT are rejected with 405.
+	OnWrite func(Item) (Item, error)
+}
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	contentType, err := negotiate(req.Header.Get("Accept"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodPost, http.MethodPut:
+		if h.OnWrite == nil {
+			http.Error(w, "this resource does not accept writes", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		item, err := h.Resource.ApplyTemplate(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		item, err = h.OnWrite(item)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if req.Method == http.MethodPut {
+			err = h.Resource.ReplaceItem(item)
+		} else {
+			err = h.Resource.AddItem(item)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.Resource.mutex.Lock()
+	body, err := h.Resource.Marshal()
+	errval := h.Resource.Collection.Error
+	h.Resource.mutex.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if errval != nil {
+		if code, ok := CodeStatus[errval.Code]; ok {
+			status = code
+		} else {
+			status = http.StatusInternalServerError
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// negotiate picks the response Content-Type from an Accept header,
+// preferring cljs.ContentType, falling back to "application/json", and
+// returning an error (which the caller should turn into a 406) if neither
+// is acceptable.
+func negotiate(accept string) (string, error) {
+	if accept == "" {
+		return ContentType, nil
+	}
+	jsonAccepted := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch mediaType {
+		case "*/*", ContentType:
+			return ContentType, nil
+		case "application/json":
+			jsonAccepted = true
+		}
+	}
+	if jsonAccepted {
+		return "application/json", nil
+	}
+	return "", fmt.Errorf("none of the accepted content types (%s) are supported", accept)
+}
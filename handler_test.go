@@ -0,0 +1,129 @@
+package cljs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestHandler(t *testing.T) Handler {
+	t.Helper()
+	r := New("/api/widgets/")
+	err := r.SetTemplate(Template{Data: []Data{{Name: "name", Required: true}}})
+	if err != nil {
+		t.Fatalf("SetTemplate failed: %v", err)
+	}
+	return Handler{
+		Resource: r,
+		OnWrite: func(item Item) (Item, error) {
+			return item, nil
+		},
+	}
+}
+
+func TestHandlerNegotiatesDefaultContentType(t *testing.T) {
+	h := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Fatalf("expected Content-Type %q, got %q", ContentType, got)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestHandlerFallsBackToJSON(t *testing.T) {
+	h := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets/", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected Content-Type application/json, got %q", got)
+	}
+}
+
+func TestHandlerRejectsUnacceptableContentType(t *testing.T) {
+	h := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets/", nil)
+	req.Header.Set("Accept", "text/plain")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotAcceptable {
+		t.Fatalf("expected 406, got %d", w.Code)
+	}
+}
+
+func TestHandlerMapsErrorCodeToStatus(t *testing.T) {
+	h := newTestHandler(t)
+	err := h.Resource.SetError(Error{Code: "404", Message: "widget not found"})
+	if err != nil {
+		t.Fatalf("SetError failed: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/widgets/", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", w.Code)
+	}
+}
+
+func TestHandlerPostAppliesTemplate(t *testing.T) {
+	h := newTestHandler(t)
+	body := `{"template":{"data":[{"name":"name","value":"sprocket"}]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/widgets/", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(h.Resource.Collection.Items) != 1 {
+		t.Fatalf("expected the posted template to produce one item, got %d", len(h.Resource.Collection.Items))
+	}
+}
+
+func TestHandlerPutReplacesExistingItem(t *testing.T) {
+	h := newTestHandler(t)
+	body := `{"template":{"data":[{"name":"name","value":"sprocket"}]}}`
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPut, "/api/widgets/", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("PUT %d: expected 200, got %d: %s", i, w.Code, w.Body.String())
+		}
+	}
+
+	if len(h.Resource.Collection.Items) != 1 {
+		t.Fatalf("expected a repeated PUT to converge on one item, got %d", len(h.Resource.Collection.Items))
+	}
+}
+
+func TestHandlerConcurrentServeHTTPAndSetError(t *testing.T) {
+	h := newTestHandler(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/api/widgets/", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+		}()
+		go func() {
+			defer wg.Done()
+			h.Resource.SetError(Error{Code: "404", Message: "widget not found"})
+		}()
+	}
+	wg.Wait()
+}
@@ -56,12 +56,7 @@ func (r *Resource) AddQuery(query Query) (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to validate query: %v", err)
 	}
-	// allocate in the resource if not exist
-	r.Collection.Queries = make([]Query, 0)
-	var tmpqueries []Query
-	tmpqueries = r.Collection.Queries
-	tmpqueries = append(tmpqueries, query)
-	r.Collection.Queries = tmpqueries
+	r.Collection.Queries = append(r.Collection.Queries, query)
 	return
 }
 
@@ -72,5 +67,11 @@ func (query Query) Validate() (err error) {
 	if query.Href == "" {
 		return fmt.Errorf("'href' attr is empty")
 	}
+	for i, data := range query.Data {
+		err = data.Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate data %d: %v", i, err)
+		}
+	}
 	return
 }
@@ -0,0 +1,15 @@
+package cljs
+
+import "testing"
+
+func TestQueryValidateChecksData(t *testing.T) {
+	query := Query{
+		Rel:  "search",
+		Href: "/api/widgets/search",
+		Data: []Data{{Name: "size", Type: "number", Value: "not-a-number"}},
+	}
+	err := query.Validate()
+	if err == nil {
+		t.Fatal("expected an error for an invalid data value, got nil")
+	}
+}
@@ -0,0 +1,225 @@
+/* Go module for Collection+JSON
+
+Version: MPL 1.1/GPL 2.0/LGPL 2.1
+
+The contents of this file are subject to the Mozilla Public License Version
+1.1 (the "License"); you may not use this file except in compliance with
+the License. You may obtain a copy of the License at
+http://www.mozilla.org/MPL/
+
+Software distributed under the License is distributed on an "AS IS" basis,
+WITHOUT WARRANTY OF ANY KIND, either express or implied. See the License
+for the specific language governing rights and limitations under the
+License.
+
+The Initial Developer of the Original Code is
+Mozilla Corporation
+Portions created by the Initial Developer are Copyright (C) 2014
+the Initial Developer. All Rights Reserved.
+
+Contributor(s):
+Julien Vehent jvehent@mozilla.com [:ulfr]
+
+Alternatively, the contents of this file may be used under the terms of
+either the GNU General Public License Version 2 or later (the "GPL"), or
+the GNU Lesser General Public License Version 2.1 or later (the "LGPL"),
+in which case the provisions of the GPL or the LGPL are applicable instead
+of those above. If you wish to allow use of your version of this file only
+under the terms of either the GPL or the LGPL, and not to allow others to
+use your version of this file under the terms of the MPL, indicate your
+decision by deleting the provisions above and replace them with the notice
+and other provisions required by the GPL or the LGPL. If you do not delete
+the provisions above, a recipient may use your version of this file under
+the terms of any one of the MPL, the GPL or the LGPL.
+*/
+
+package cljs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Template carries the data a client should fill in and post back to
+// create or edit a resource.
+type Template struct {
+	Data []Data `json:"data,omitempty"` //optional
+}
+
+// TemplateErrorKind identifies why a posted Template could not be applied
+// to a resource's stored template, so an HTTP handler can pick the right
+// 4xx status without parsing an error string.
+type TemplateErrorKind string
+
+const (
+	UnknownDataName   TemplateErrorKind = "unknown-data-name"
+	MissingDataName   TemplateErrorKind = "missing-data-name"
+	DuplicateDataName TemplateErrorKind = "duplicate-data-name"
+	ReadOnlyDataName  TemplateErrorKind = "read-only-data-name"
+	InvalidDataValue  TemplateErrorKind = "invalid-data-value"
+)
+
+// TemplateError is returned by Template.Apply and Resource.ApplyTemplate
+// when the posted template doesn't match the resource's stored template.
+// Err holds the underlying validation error when Kind is InvalidDataValue.
+type TemplateError struct {
+	Kind TemplateErrorKind
+	Name string
+	Err  error
+}
+
+func (e *TemplateError) Error() string {
+	switch e.Kind {
+	case UnknownDataName:
+		return fmt.Sprintf("posted template contains unknown data name %q", e.Name)
+	case MissingDataName:
+		return fmt.Sprintf("posted template is missing required data name %q", e.Name)
+	case DuplicateDataName:
+		return fmt.Sprintf("posted template contains data name %q more than once", e.Name)
+	case ReadOnlyDataName:
+		return fmt.Sprintf("posted template attempts to modify read-only data name %q", e.Name)
+	case InvalidDataValue:
+		return fmt.Sprintf("posted template has an invalid value for %q: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("invalid data name %q", e.Name)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+func (r *Resource) SetTemplate(template Template) (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// syntax checking
+	err = template.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate template: %v", err)
+	}
+	r.Collection.Template = &template
+	return
+}
+
+// Validate checks the syntax of a stored or posted template. It does not
+// enforce Required or ReadOnly: those constrain how a *posted* template
+// relates to the schema it is filled in from, which only Template.Apply
+// can judge. Validate does compile every field's Regex eagerly (caching
+// the result on the Data itself), so a malformed pattern is rejected here
+// instead of surfacing later, at Apply time, when a matching value happens
+// to be posted.
+func (template Template) Validate() (err error) {
+	for i := range template.Data {
+		err = template.Data[i].compileRegex()
+		if err != nil {
+			return err
+		}
+		err = template.Data[i].Validate()
+		if err != nil {
+			return fmt.Errorf("failed to validate data %d: %v", i, err)
+		}
+	}
+	return
+}
+
+// Apply merges the values of a posted template into a new Item at href.
+// posted is the template a client has filled in and sent back to the API;
+// the receiver, schema, is the resource's stored template that defines
+// which data names are allowed, and carries their value extensions
+// (required, readOnly, regex, type, min, max). Every name in posted must
+// be present in schema, and may appear at most once; every required name
+// in schema must be present in posted with a non-empty value; read-only
+// names may not change value; and every value must pass Data.Validate
+// against the schema's Type and Regex constraints. Any violation returns
+// a *TemplateError identifying the offending name.
+func (schema Template) Apply(posted Template, href string) (item Item, err error) {
+	if href == "" {
+		return item, fmt.Errorf("'href' is required to apply a template")
+	}
+	bySchemaName := make(map[string]Data, len(schema.Data))
+	for _, data := range schema.Data {
+		bySchemaName[data.Name] = data
+	}
+	seen := make(map[string]bool, len(posted.Data))
+	itemData := make([]Data, 0, len(schema.Data))
+	for _, posted := range posted.Data {
+		schemaData, ok := bySchemaName[posted.Name]
+		if !ok {
+			return item, &TemplateError{Kind: UnknownDataName, Name: posted.Name}
+		}
+		if seen[posted.Name] {
+			return item, &TemplateError{Kind: DuplicateDataName, Name: posted.Name}
+		}
+		if schemaData.ReadOnly && !reflect.DeepEqual(posted.Value, schemaData.Value) {
+			return item, &TemplateError{Kind: ReadOnlyDataName, Name: posted.Name}
+		}
+		if schemaData.Required && (posted.Value == nil || posted.Value == "") {
+			return item, &TemplateError{Kind: MissingDataName, Name: posted.Name}
+		}
+		merged := schemaData
+		merged.Value = posted.Value
+		err = merged.Validate()
+		if err != nil {
+			return item, &TemplateError{Kind: InvalidDataValue, Name: posted.Name, Err: err}
+		}
+		seen[posted.Name] = true
+		itemData = append(itemData, merged)
+	}
+	for _, schemaData := range schema.Data {
+		if seen[schemaData.Name] {
+			continue
+		}
+		if schemaData.Required {
+			return item, &TemplateError{Kind: MissingDataName, Name: schemaData.Name}
+		}
+		// optional and not posted: carry the schema's own value forward
+		itemData = append(itemData, schemaData)
+	}
+	item = Item{Href: href, Data: itemData}
+	err = item.Validate()
+	if err != nil {
+		return item, fmt.Errorf("failed to validate applied item: %v", err)
+	}
+	return item, nil
+}
+
+// templateDocument mirrors the body a client posts when submitting a
+// filled-in template, eg `{"template": {"data": [...]}}`.
+type templateDocument struct {
+	Template *Template `json:"template"`
+}
+
+// ApplyTemplate unmarshals a posted template document and applies it
+// against the resource's stored template, returning an Item ready to be
+// persisted and/or added to the resource with AddItem.
+func (r *Resource) ApplyTemplate(body []byte) (item Item, err error) {
+	var doc templateDocument
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&doc)
+	if err != nil {
+		return item, fmt.Errorf("failed to parse posted template: %v", err)
+	}
+	if doc.Template == nil {
+		return item, fmt.Errorf("posted body is missing the 'template' member")
+	}
+	err = doc.Template.Validate()
+	if err != nil {
+		return item, fmt.Errorf("failed to validate posted template: %v", err)
+	}
+
+	r.mutex.Lock()
+	schema := r.Collection.Template
+	href := r.Collection.Href
+	r.mutex.Unlock()
+	if schema == nil {
+		return item, fmt.Errorf("resource has no template to apply the posted data against")
+	}
+	if href == "" {
+		return item, fmt.Errorf("resource has no href to build the item from")
+	}
+
+	return schema.Apply(*doc.Template, href)
+}
@@ -0,0 +1,96 @@
+package cljs
+
+import (
+	"errors"
+	"testing"
+)
+
+func schemaTemplate() Template {
+	return Template{Data: []Data{
+		{Name: "email", Required: true, Regex: `^\S+@\S+$`},
+		{Name: "plan", ReadOnly: true, Value: "free"},
+		{Name: "nickname"},
+	}}
+}
+
+func TestTemplateApplySuccess(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "email", Value: "bob@example.org"},
+		{Name: "plan", Value: "free"},
+	}}
+	item, err := schema.Apply(posted, "/api/bob")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if item.Href != "/api/bob" {
+		t.Fatalf("unexpected href: %s", item.Href)
+	}
+	if len(item.Data) != 3 {
+		t.Fatalf("expected 3 data fields (email, plan, nickname), got %d: %+v", len(item.Data), item.Data)
+	}
+}
+
+func TestTemplateApplyUnknownDataName(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "email", Value: "bob@example.org"},
+		{Name: "plan", Value: "free"},
+		{Name: "not-in-schema", Value: "x"},
+	}}
+	_, err := schema.Apply(posted, "/api/bob")
+	assertTemplateErrorKind(t, err, UnknownDataName)
+}
+
+func TestTemplateApplyMissingDataName(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "plan", Value: "free"},
+	}}
+	_, err := schema.Apply(posted, "/api/bob")
+	assertTemplateErrorKind(t, err, MissingDataName)
+}
+
+func TestTemplateApplyDuplicateDataName(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "email", Value: "bob@example.org"},
+		{Name: "email", Value: "bob@example.org"},
+	}}
+	_, err := schema.Apply(posted, "/api/bob")
+	assertTemplateErrorKind(t, err, DuplicateDataName)
+}
+
+func TestTemplateApplyReadOnlyDataName(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "email", Value: "bob@example.org"},
+		{Name: "plan", Value: "pro"},
+	}}
+	_, err := schema.Apply(posted, "/api/bob")
+	assertTemplateErrorKind(t, err, ReadOnlyDataName)
+}
+
+func TestTemplateApplyInvalidDataValue(t *testing.T) {
+	schema := schemaTemplate()
+	posted := Template{Data: []Data{
+		{Name: "email", Value: "not-an-email"},
+		{Name: "plan", Value: "free"},
+	}}
+	_, err := schema.Apply(posted, "/api/bob")
+	assertTemplateErrorKind(t, err, InvalidDataValue)
+}
+
+func assertTemplateErrorKind(t *testing.T, err error, kind TemplateErrorKind) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected a %s error, got nil", kind)
+	}
+	var terr *TemplateError
+	if !errors.As(err, &terr) {
+		t.Fatalf("expected a *TemplateError, got %T: %v", err, err)
+	}
+	if terr.Kind != kind {
+		t.Fatalf("expected kind %s, got %s (%v)", kind, terr.Kind, err)
+	}
+}
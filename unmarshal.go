@@ -0,0 +1,80 @@
+/* Go module for Collection+JSON
+
+Version: MPL 1.1/GPL 2.0/LGPL 2.1
+
+The contents of this file are subject to the Mozilla Public License Version
+1.1 (the "License"); you may not use this file except in compliance with
+the License. You may obtain a copy of the License at
+http://www.mozilla.org/MPL/
+
+Software distributed under the License is distributed on an "AS IS" basis,
+WITHOUT WARRANTY OF ANY KIND, either express or implied. See the License
+for the specific language governing rights and limitations under the
+License.
+
+The Initial Developer of the Original Code is
+Mozilla Corporation
+Portions created by the Initial Developer are Copyright (C) 2014
+the Initial Developer. All Rights Reserved.
+
+Contributor(s):
+Julien Vehent jvehent@mozilla.com [:ulfr]
+
+Alternatively, the contents of this file may be used under the terms of
+either the GNU General Public License Version 2 or later (the "GPL"), or
+the GNU Lesser General Public License Version 2.1 or later (the "LGPL"),
+in which case the provisions of the GPL or the LGPL are applicable instead
+of those above. If you wish to allow use of your version of this file only
+under the terms of either the GPL or the LGPL, and not to allow others to
+use your version of this file under the terms of the MPL, indicate your
+decision by deleting the provisions above and replace them with the notice
+and other provisions required by the GPL or the LGPL. If you do not delete
+the provisions above, a recipient may use your version of this file under
+the terms of any one of the MPL, the GPL or the LGPL.
+*/
+
+package cljs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// wireDocument mirrors a whole Collection+JSON document. It decodes
+// straight into Collection's concrete types, so no further conversion is
+// needed before storing the result on a Resource.
+type wireDocument struct {
+	Collection *Collection `json:"collection"`
+}
+
+// Unmarshal parses a Collection+JSON document and returns a validated
+// Resource. Unknown top-level members are rejected, and every link, item,
+// query, template and error found in the document is run through its
+// Validate method before the Resource is handed back to the caller.
+func Unmarshal(data []byte) (*Resource, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode is the streaming equivalent of Unmarshal, for callers that already
+// have an io.Reader, such as an *http.Request body.
+func Decode(reader io.Reader) (r *Resource, err error) {
+	var doc wireDocument
+	dec := json.NewDecoder(reader)
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse collection+json document: %v", err)
+	}
+	if doc.Collection == nil {
+		return nil, fmt.Errorf("document is missing the 'collection' member")
+	}
+
+	r = &Resource{Collection: *doc.Collection}
+	err = r.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,65 @@
+package cljs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnmarshalRoundTrip(t *testing.T) {
+	r := New("http://example.org/friends/")
+	err := r.AddLink(Link{Rel: "feed", Href: "http://example.org/friends/rss"})
+	if err != nil {
+		t.Fatalf("AddLink failed: %v", err)
+	}
+	err = r.AddItem(Item{
+		Href: "http://example.org/friends/jdoe",
+		Data: []Data{{Name: "full-name", Value: "J. Doe"}},
+	})
+	if err != nil {
+		t.Fatalf("AddItem failed: %v", err)
+	}
+
+	body, err := r.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	decoded, err := Unmarshal(body)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	roundTripped, err := decoded.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal of decoded resource failed: %v", err)
+	}
+	if string(roundTripped) != string(body) {
+		t.Fatalf("round trip mismatch:\ngot:  %s\nwant: %s", roundTripped, body)
+	}
+	if !strings.HasPrefix(string(body), `{"collection":{"version":"1.0","href":`) {
+		t.Fatalf("version/href are not marshalled first: %s", body)
+	}
+}
+
+func TestUnmarshalRejectsUnknownFields(t *testing.T) {
+	doc := `{"collection":{"version":"1.0","href":"/api/","bogus":true}}`
+	_, err := Unmarshal([]byte(doc))
+	if err == nil {
+		t.Fatal("expected an error for an unknown top-level member, got nil")
+	}
+}
+
+// A required template field is legitimately empty until a client fills it
+// in: Unmarshal must accept such a document rather than rejecting it as if
+// the required field belonged to a filled-in item.
+func TestUnmarshalAcceptsRequiredTemplateField(t *testing.T) {
+	doc := `{"collection":{"version":"1.0","href":"/api/","template":` +
+		`{"data":[{"name":"email","required":true,"value":""}]}}}`
+	r, err := Unmarshal([]byte(doc))
+	if err != nil {
+		t.Fatalf("Unmarshal rejected a template with an unfilled required field: %v", err)
+	}
+	if r.Collection.Template == nil || len(r.Collection.Template.Data) != 1 {
+		t.Fatalf("template was not decoded: %+v", r.Collection)
+	}
+}